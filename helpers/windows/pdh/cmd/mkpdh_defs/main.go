@@ -0,0 +1,280 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+// mkpdh_defs reads the numeric values of the PDH_* error codes, the
+// PERF_* counter type flags, and the PDH_LOG_TYPE_* log format constants out
+// of the Windows SDK headers (via a throwaway C translation unit compiled
+// with gcc) and emits them as plain Go constants. The generator itself
+// still needs a C toolchain with the SDK headers, but the *output* has no
+// cgo in it, so building elastic-agent-libs (or anything that imports the
+// pdh package) never requires one.
+//
+// It lives in its own cmd/ package, rather than alongside the pdh package
+// it generates code for, so that `go test ./...` can actually exercise its
+// pure helper functions instead of needing a `//go:build ignore` tag to
+// keep `package main` out of the pdh package's build.
+//
+// go run ./helpers/windows/pdh/cmd/mkpdh_defs
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var (
+	output = flag.String("output", "helpers/windows/pdh/defs_pdh_windows.go", "output file")
+)
+
+const includes = `
+#include <windows.h>
+#include <winperf.h>
+#include <pdh.h>
+#include <pdhmsg.h>
+`
+
+// cDefine is a single #define captured from the preprocessor dump, with its
+// value normalized to a Go integer literal.
+type cDefine struct {
+	Name  string
+	Value string
+}
+
+type TemplateParams struct {
+	Errors       []cDefine
+	LogTypes     []cDefine
+	PerfType     []cDefine
+	DetailLevels []cDefine
+}
+
+const fileTemplate = `
+// MACHINE GENERATED BY THE COMMAND BELOW; DO NOT EDIT
+// go run ./helpers/windows/pdh/cmd/mkpdh_defs
+
+//go:build windows
+
+package pdh
+
+// PdhErrno is the error type returned by the PDH_* APIs. The values below are
+// hard-coded from the Windows SDK headers so that this package never
+// requires cgo or a C toolchain to build.
+type PdhErrno uintptr
+
+// PDH Error Codes
+const (
+{{- range $i, $def := .Errors }}
+	{{ $def.Name }} PdhErrno = {{ $def.Value }}
+{{- end }}
+)
+
+type PdhCounterFormat uint32
+
+// PDH Counter Formats
+const (
+	// PdhFmtDouble returns data as a double-precision floating point real.
+	PdhFmtDouble PdhCounterFormat = 0x00000200
+	// PdhFmtLarge returns data as a 64-bit integer.
+	PdhFmtLarge PdhCounterFormat = 0x00000400
+	// PdhFmtLong returns data as a long integer.
+	PdhFmtLong PdhCounterFormat = 0x00000100
+
+	// Use bitwise operators to combine these values with the counter type to scale the value.
+
+	// PdhFmtNoScale does not apply the counter's default scaling factor.
+	PdhFmtNoScale PdhCounterFormat = 0x00001000
+	// PdhFmtNoCap100 allows counter values greater than 100 (for example,
+	// counter values measuring the processor load on multiprocessor
+	// computers) to not be reset to 100. The default behavior is that
+	// counter values are capped at a value of 100.
+	PdhFmtNoCap100 PdhCounterFormat = 0x00008000
+	// PdhFmtMultiply1000 multiplies the actual value by 1,000.
+	PdhFmtMultiply1000 PdhCounterFormat = 0x00002000
+)
+
+// PdhLogType identifies the on-disk format of a performance log opened with
+// PdhOpenLog, such as a binary .blg capture or a delimited text export.
+type PdhLogType uint32
+
+// PDH Log File Types
+const (
+{{- range $i, $def := .LogTypes }}
+	{{ $def.Name }} PdhLogType = {{ $def.Value }}
+{{- end }}
+)
+
+// CounterType classifies the raw counter metadata returned by
+// PdhGetCounterInfo (its DWORD CounterType field), which combines a
+// calculation kind (rate, fraction, histogram bucket, ...) with display and
+// timer-base flags from winperf.h.
+type CounterType uint32
+
+// PERF_* counter type flags, decoded from winperf.h.
+const (
+{{- range $i, $def := .PerfType }}
+	{{ $def.Name }} CounterType = {{ $def.Value }}
+{{- end }}
+)
+
+// PdhDetailLevel selects how much of a performance object's counter set
+// PdhEnumObjects/PdhEnumObjectItems returns, from winperf.h's PERF_DETAIL_*
+// levels.
+type PdhDetailLevel uint32
+
+// PERF_DETAIL_* enumeration detail levels, decoded from winperf.h.
+const (
+{{- range $i, $def := .DetailLevels }}
+	{{ $def.Name }} PdhDetailLevel = {{ $def.Value }}
+{{- end }}
+)
+
+// perfCalculationMask isolates the calculation-kind bits (rate, fraction,
+// base, elapsed time, queue length, histogram, precision) of a CounterType.
+const perfCalculationMask CounterType = 0x00070000
+
+// IsRate reports whether ct is a rate counter (a per-second delta between
+// two samples), such as "% Processor Time" or "Disk Reads/sec". Rate
+// counters require two PdhCollectQueryData samples before they have a
+// valid formatted value.
+func (ct CounterType) IsRate() bool {
+	return ct&perfCalculationMask == PERF_COUNTER_RATE
+}
+
+// IsHistogram reports whether ct represents a histogram-type counter, whose
+// raw value is a bucket count rather than a point-in-time measurement or a
+// rate.
+func (ct CounterType) IsHistogram() bool {
+	return ct&perfCalculationMask == PERF_COUNTER_HISTOGRAM
+}
+
+// Scale returns the multiplier that should be applied to a raw counter
+// value of this type to convert it to base units (seconds, bytes, ...). It
+// is 1 for every counter type except those whose raw value is expressed in
+// 100-nanosecond ticks, such as "% Processor Time" and other timer
+// counters, which scale by 100ns.
+func (ct CounterType) Scale() float64 {
+	if ct&PERF_TIMER_100NS == PERF_TIMER_100NS {
+		return 100e-9
+	}
+	return 1
+}
+`
+
+var (
+	tmpl = template.Must(template.New("defs_pdh_windows").Parse(fileTemplate))
+
+	// Matches "#define PDH_FOO ((PDH_STATUS)0x800007D0L)" style lines emitted
+	// by `gcc -E -dD`.
+	pdhErrorRegex   = regexp.MustCompile(`^#define (PDH_[\w]+)\s+(.+)$`)
+	logTypeRegex    = regexp.MustCompile(`^PDH_LOG_TYPE_`)
+	perfTypeRegex   = regexp.MustCompile(`^#define (PERF_[\w]+)\s+(.+)$`)
+	perfDetailRegex = regexp.MustCompile(`^PERF_DETAIL_`)
+)
+
+func main() {
+	dump, err := preprocess()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	// PDH_LOG_TYPE_NOT_FOUND shares the log type prefix but is a pdhmsg.h
+	// error code, so it is kept with the errors.
+	t := TemplateParams{
+		Errors:       scan(dump, pdhErrorRegex, func(name string) bool { return !logTypeRegex.MatchString(name) || name == "PDH_LOG_TYPE_NOT_FOUND" }),
+		LogTypes:     scan(dump, pdhErrorRegex, func(name string) bool { return logTypeRegex.MatchString(name) && name != "PDH_LOG_TYPE_NOT_FOUND" }),
+		PerfType:     scan(dump, perfTypeRegex, func(name string) bool { return !perfDetailRegex.MatchString(name) }),
+		DetailLevels: scan(dump, perfTypeRegex, func(name string) bool { return perfDetailRegex.MatchString(name) }),
+	}
+
+	if err := writeOutput(t); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	if err := gofmtOutput(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func preprocess() ([]byte, error) {
+	cmd := exec.Command("gcc", "-E", "-dD", "-")
+	cmd.Stdin = bytes.NewBuffer([]byte(includes))
+	return cmd.Output()
+}
+
+// scan extracts every #define matched by re out of dump, keeping only those
+// for which keep (when non-nil) returns true.
+func scan(dump []byte, re *regexp.Regexp, keep func(name string) bool) []cDefine {
+	var defs []cDefine
+	seen := make(map[string]bool)
+	s := bufio.NewScanner(bytes.NewBuffer(dump))
+	for s.Scan() {
+		matches := re.FindStringSubmatch(s.Text())
+		if len(matches) <= 2 || seen[matches[1]] {
+			continue
+		}
+		if keep != nil && !keep(matches[1]) {
+			continue
+		}
+
+		seen[matches[1]] = true
+		defs = append(defs, cDefine{Name: matches[1], Value: normalizeValue(matches[2])})
+	}
+	return defs
+}
+
+// normalizeValue converts a C integer literal/cast expression, such as
+// "((PDH_STATUS)0x800007D0L)", into a bare Go integer literal such as
+// "0x800007D0".
+func normalizeValue(raw string) string {
+	v := strings.TrimSpace(raw)
+	if idx := strings.Index(v, ")"); idx != -1 {
+		v = v[idx+1:]
+	}
+	v = strings.TrimRight(v, "()")
+	v = strings.TrimSuffix(v, "L")
+	v = strings.TrimSuffix(v, "l")
+	return strings.TrimSpace(v)
+}
+
+func writeOutput(p TemplateParams) error {
+	// Create output file.
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, p); err != nil {
+		return err
+	}
+	return nil
+}
+
+func gofmtOutput() error {
+	_, err := exec.Command("gofmt", "-w", *output).Output()
+	return err
+}
@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"cast with L suffix", "((PDH_STATUS)0x800007D0L)", "0x800007D0"},
+		{"cast with lowercase l suffix", "((PDH_STATUS)0x800007D0l)", "0x800007D0"},
+		{"bare hex literal", "0x00010000", "0x00010000"},
+		{"decimal literal", "100", "100"},
+		{"surrounding whitespace", "  0x1  ", "0x1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeValue(tt.raw); got != tt.want {
+				t.Errorf("normalizeValue(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	dump := []byte(
+		"#define PDH_CSTATUS_NO_MACHINE ((PDH_STATUS)0x800007D0L)\n" +
+			"#define PDH_LOG_TYPE_CSV ((PDH_STATUS)1L)\n" +
+			"#define PDH_CSTATUS_NO_MACHINE ((PDH_STATUS)0x800007D0L)\n" + // duplicate, must be deduped
+			"#define PERF_COUNTER_RATE 0x00010000\n" +
+			"not a define line\n",
+	)
+
+	got := scan(dump, pdhErrorRegex, func(name string) bool { return !logTypeRegex.MatchString(name) })
+	want := []cDefine{{Name: "PDH_CSTATUS_NO_MACHINE", Value: "0x800007D0"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scan() = %+v, want %+v", got, want)
+	}
+
+	logTypes := scan(dump, pdhErrorRegex, func(name string) bool { return logTypeRegex.MatchString(name) })
+	wantLogTypes := []cDefine{{Name: "PDH_LOG_TYPE_CSV", Value: "1"}}
+	if !reflect.DeepEqual(logTypes, wantLogTypes) {
+		t.Errorf("scan() log types = %+v, want %+v", logTypes, wantLogTypes)
+	}
+}
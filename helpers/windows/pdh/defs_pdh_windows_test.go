@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+import "testing"
+
+func TestCounterTypeIsRate(t *testing.T) {
+	tests := []struct {
+		name string
+		ct   CounterType
+		want bool
+	}{
+		{"rate counter", PERF_COUNTER_RATE, true},
+		{"rate counter with timer flag", PERF_COUNTER_RATE | PERF_TIMER_100NS, true},
+		{"histogram counter", PERF_COUNTER_HISTOGRAM, false},
+		{"base counter", PERF_COUNTER_BASE, false},
+		{"zero value", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ct.IsRate(); got != tt.want {
+				t.Errorf("CounterType(0x%X).IsRate() = %v, want %v", uint32(tt.ct), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCounterTypeIsHistogram(t *testing.T) {
+	tests := []struct {
+		name string
+		ct   CounterType
+		want bool
+	}{
+		{"histogram counter", PERF_COUNTER_HISTOGRAM, true},
+		{"histogram counter with timer flag", PERF_COUNTER_HISTOGRAM | PERF_TIMER_100NS, true},
+		{"rate counter", PERF_COUNTER_RATE, false},
+		{"zero value", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ct.IsHistogram(); got != tt.want {
+				t.Errorf("CounterType(0x%X).IsHistogram() = %v, want %v", uint32(tt.ct), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCounterTypeScale(t *testing.T) {
+	tests := []struct {
+		name string
+		ct   CounterType
+		want float64
+	}{
+		{"100ns timer counter", PERF_COUNTER_RATE | PERF_TIMER_100NS, 100e-9},
+		{"non-timer rate counter", PERF_COUNTER_RATE, 1},
+		{"zero value", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ct.Scale(); got != tt.want {
+				t.Errorf("CounterType(0x%X).Scale() = %v, want %v", uint32(tt.ct), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/sys/windows"
+)
+
+// ErrEndOfLog is wrapped by the error LogReader.Next returns once every
+// sample in the log has been read.
+var ErrEndOfLog = errors.New("pdh: end of log file")
+
+// LogReader replays a Windows Performance Monitor log (a binary .blg, or a
+// delimited .csv/.tsv export) sample by sample, so historical captures can
+// be ingested the same way a live Query is, rather than only supporting
+// real-time counters.
+type LogReader struct {
+	path       string
+	dataSource PDH_HLOG
+	query      PDH_HQUERY
+	counters   map[string]*counterInfo
+}
+
+// OpenLogReader binds to the single log file named by path and opens a query
+// against it. A path containing NUL characters (such as a NUL-separated list
+// of several files) is rejected by the UTF-16 conversion.
+func OpenLogReader(path string) (*LogReader, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataSource PDH_HLOG
+	if err := pdhErrnoOrNil(pdhBindInputDataSource(&dataSource, pathPtr)); err != nil {
+		return nil, fmt.Errorf("failed binding PDH log %q: %w", path, err)
+	}
+
+	var query PDH_HQUERY
+	if err := pdhErrnoOrNil(pdhOpenQueryH(dataSource, 0, &query)); err != nil {
+		pdhCloseLog(dataSource)
+		return nil, fmt.Errorf("failed opening query against PDH log %q: %w", path, err)
+	}
+
+	return &LogReader{
+		path:       path,
+		dataSource: dataSource,
+		query:      query,
+		counters:   make(map[string]*counterInfo),
+	}, nil
+}
+
+// Objects lists the performance objects (e.g. "Processor", "PhysicalDisk")
+// captured in the log.
+func (r *LogReader) Objects() ([]string, error) {
+	pathPtr, err := windows.UTF16PtrFromString(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var size uint32
+	err = pdhErrnoOrNil(pdhEnumObjects(pathPtr, nil, nil, &size, uint32(PERF_DETAIL_WIZARD), 0))
+	if err != nil && err != PdhErrno(PDH_MORE_DATA) {
+		return nil, fmt.Errorf("failed enumerating PDH log objects: %w", err)
+	}
+	if size == 0 {
+		return nil, nil
+	}
+
+	buf := make([]uint16, size)
+	if err := pdhErrnoOrNil(pdhEnumObjects(pathPtr, nil, &buf[0], &size, uint32(PERF_DETAIL_WIZARD), 0)); err != nil {
+		return nil, fmt.Errorf("failed enumerating PDH log objects: %w", err)
+	}
+	return splitNulTerminatedStrings(buf), nil
+}
+
+// ObjectItems lists the counters and instances that object contributes to
+// the log, e.g. object "PhysicalDisk" might return counters including
+// "Disk Reads/sec" and instances including "0 C:".
+func (r *LogReader) ObjectItems(object string) (counters []string, instances []string, err error) {
+	pathPtr, err := windows.UTF16PtrFromString(r.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	objectPtr, err := windows.UTF16PtrFromString(object)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var counterSize, instanceSize uint32
+	err = pdhErrnoOrNil(pdhEnumObjectItems(pathPtr, nil, objectPtr, nil, &counterSize, nil, &instanceSize, uint32(PERF_DETAIL_WIZARD), 0))
+	if err != nil && err != PdhErrno(PDH_MORE_DATA) {
+		return nil, nil, fmt.Errorf("failed enumerating items of PDH log object %q: %w", object, err)
+	}
+
+	var counterBuf, instanceBuf []uint16
+	var counterPtr, instancePtr *uint16
+	if counterSize > 0 {
+		counterBuf = make([]uint16, counterSize)
+		counterPtr = &counterBuf[0]
+	}
+	if instanceSize > 0 {
+		instanceBuf = make([]uint16, instanceSize)
+		instancePtr = &instanceBuf[0]
+	}
+	if counterPtr == nil && instancePtr == nil {
+		return nil, nil, nil
+	}
+
+	if err := pdhErrnoOrNil(pdhEnumObjectItems(pathPtr, nil, objectPtr, counterPtr, &counterSize, instancePtr, &instanceSize, uint32(PERF_DETAIL_WIZARD), 0)); err != nil {
+		return nil, nil, fmt.Errorf("failed enumerating items of PDH log object %q: %w", object, err)
+	}
+	return splitNulTerminatedStrings(counterBuf), splitNulTerminatedStrings(instanceBuf), nil
+}
+
+// AddCounter adds path (e.g. "\PhysicalDisk(0 C:)\Disk Reads/sec") to the
+// reader, formatting collected samples according to format.
+func (r *LogReader) AddCounter(path string, instance string, format PdhCounterFormat) error {
+	handle, err := PdhAddCounter(r.query, path)
+	if err != nil {
+		return fmt.Errorf("failed adding counter %q to PDH log reader: %w", path, err)
+	}
+
+	r.counters[path] = &counterInfo{path: path, instance: instance, format: format, handle: handle}
+	return nil
+}
+
+// Next advances to the next timestamped sample in the log and returns its
+// time (as a Windows FILETIME). Once the log has been fully read it returns
+// an error that wraps both ErrEndOfLog and io.EOF, so errors.Is matches
+// either one.
+func (r *LogReader) Next() (int64, error) {
+	timeStamp, err := PdhCollectQueryDataWithTime(r.query)
+	if err == nil {
+		return timeStamp, nil
+	}
+
+	var errno PdhErrno
+	if errors.As(err, &errno) && (errno == PdhErrno(PDH_NO_MORE_DATA) || errno == PdhErrno(PDH_END_OF_LOG_FILE)) {
+		return 0, fmt.Errorf("%w: %w", ErrEndOfLog, io.EOF)
+	}
+	return 0, err
+}
+
+// Values returns the formatted value(s) read by the most recent call to
+// Next, keyed by counter path, in the same shape as
+// Query.GetFormattedCounterValues.
+func (r *LogReader) Values() (map[string][]CounterValue, error) {
+	results := make(map[string][]CounterValue, len(r.counters))
+	for path, info := range r.counters {
+		value, err := PdhGetFormattedCounterValue(info.handle, info.format)
+		if err != nil {
+			return nil, fmt.Errorf("failed formatting counter value for %q: %w", path, err)
+		}
+		results[path] = append(results[path], CounterValue{Instance: info.instance, Value: value})
+	}
+	return results, nil
+}
+
+// Close closes the reader's query and releases the bound log file(s).
+func (r *LogReader) Close() error {
+	closeErr := PdhCloseQuery(r.query)
+	if err := pdhErrnoOrNil(pdhCloseLog(r.dataSource)); err != nil && closeErr == nil {
+		closeErr = err
+	}
+	r.counters = make(map[string]*counterInfo)
+	return closeErr
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitNulTerminatedStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  []uint16
+		want []string
+	}{
+		{"empty buffer", nil, nil},
+		{"single trailing NUL only", []uint16{0}, nil},
+		{
+			"two strings with final NUL",
+			append(append(utf16Of("PhysicalDisk"), 0), append(utf16Of("Processor"), 0, 0)...),
+			[]string{"PhysicalDisk", "Processor"},
+		},
+		{
+			"empty entries are skipped",
+			[]uint16{0, 0},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitNulTerminatedStrings(tt.buf); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitNulTerminatedStrings(%v) = %v, want %v", tt.buf, got, tt.want)
+			}
+		})
+	}
+}
+
+func utf16Of(s string) []uint16 {
+	out := make([]uint16, len(s))
+	for i, r := range s {
+		out[i] = uint16(r)
+	}
+	return out
+}
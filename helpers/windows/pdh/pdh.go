@@ -0,0 +1,334 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+// Package pdh provides low-level bindings to the Windows Performance Data
+// Helper (PDH) API, used to read performance counters such as those shown by
+// Perfmon. The bindings are pure Go (generated with mkwinsyscall against
+// golang.org/x/sys/windows) so importing this package never requires cgo.
+package pdh
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"golang.org/x/sys/windows"
+)
+
+// PDH_HQUERY is a handle to a PDH query, obtained from PdhOpenQuery.
+type PDH_HQUERY uintptr
+
+// PDH_HCOUNTER is a handle to a counter added to a query via PdhAddCounter
+// or PdhAddEnglishCounter.
+type PDH_HCOUNTER uintptr
+
+// PDH_HLOG is a handle to a performance log data source (a .blg, .csv, or
+// .tsv file) opened with PdhBindInputDataSource.
+type PDH_HLOG uintptr
+
+// PDH_FMT_COUNTERVALUE mirrors the union returned by
+// PdhGetFormattedCounterValue. CStatus reports whether the formatted value
+// is valid; check it against PdhErrno's IsError before trusting the value.
+// LongValue, DoubleValue, and LargeValue all alias the same 8 bytes (the
+// union member PDH actually wrote), matching the real Windows struct layout;
+// use whichever accessor corresponds to the PdhCounterFormat requested.
+type PDH_FMT_COUNTERVALUE struct {
+	CStatus uint32
+	_       [4]byte // alignment padding before the union on amd64
+	union   [8]byte
+}
+
+// LongValue interprets the union as the int32 written for PdhFmtLong.
+func (v *PDH_FMT_COUNTERVALUE) LongValue() int32 {
+	return int32(binary.LittleEndian.Uint32(v.union[:4]))
+}
+
+// LargeValue interprets the union as the int64 written for PdhFmtLarge.
+func (v *PDH_FMT_COUNTERVALUE) LargeValue() int64 {
+	return int64(binary.LittleEndian.Uint64(v.union[:]))
+}
+
+// DoubleValue interprets the union as the float64 written for PdhFmtDouble.
+func (v *PDH_FMT_COUNTERVALUE) DoubleValue() float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(v.union[:]))
+}
+
+// NewLongCounterValue builds a PDH_FMT_COUNTERVALUE whose union holds n as
+// PdhFmtLong would, for unit tests of code that formats counter values
+// without a live PDH query.
+func NewLongCounterValue(n int32) PDH_FMT_COUNTERVALUE {
+	var v PDH_FMT_COUNTERVALUE
+	binary.LittleEndian.PutUint32(v.union[:4], uint32(n))
+	return v
+}
+
+// NewLargeCounterValue builds a PDH_FMT_COUNTERVALUE whose union holds n as
+// PdhFmtLarge would, for unit tests of code that formats counter values
+// without a live PDH query.
+func NewLargeCounterValue(n int64) PDH_FMT_COUNTERVALUE {
+	var v PDH_FMT_COUNTERVALUE
+	binary.LittleEndian.PutUint64(v.union[:], uint64(n))
+	return v
+}
+
+// NewDoubleCounterValue builds a PDH_FMT_COUNTERVALUE whose union holds f as
+// PdhFmtDouble would, for unit tests of code that formats counter values
+// without a live PDH query.
+func NewDoubleCounterValue(f float64) PDH_FMT_COUNTERVALUE {
+	var v PDH_FMT_COUNTERVALUE
+	binary.LittleEndian.PutUint64(v.union[:], math.Float64bits(f))
+	return v
+}
+
+// Error implements the error interface for PdhErrno by asking pdh.dll to
+// format the message text associated with the code, the same way
+// syscall.Errno does for kernel32 errors.
+func (e PdhErrno) Error() string {
+	b := make([]uint16, 300)
+	n, err := windows.FormatMessage(
+		windows.FORMAT_MESSAGE_FROM_HMODULE|windows.FORMAT_MESSAGE_FROM_SYSTEM|windows.FORMAT_MESSAGE_ARGUMENT_ARRAY,
+		modpdh.Handle(),
+		uint32(e),
+		0,
+		b,
+		nil,
+	)
+	if err != nil {
+		return fmt.Sprintf("PDH error 0x%X", uint32(e))
+	}
+	return windows.UTF16ToString(b[:n])
+}
+
+// IsError reports whether e represents a PDH failure (as opposed to a
+// success or informational code such as PDH_CSTATUS_NEW_DATA). Like the
+// Windows FAILED() macro, this only inspects the top (severity) bit of the
+// code rather than maintaining a hand-curated list of "known" failure codes,
+// since PDH_* and winerror.h codes alike set that bit on every code meant
+// to be treated as a failure, including ones added to pdhmsg.h after this
+// package was last regenerated. Warning codes such as PDH_MORE_DATA
+// (0x800007D2) also set that bit, so IsError reports them as errors too.
+func (e PdhErrno) IsError() bool {
+	return e&0x80000000 != 0
+}
+
+// pdhErrnoOrNil converts a raw return code from one of the zsyscall_windows.go
+// bindings into an error, returning nil for PDH_CSTATUS_VALID_DATA and other
+// non-error (success/informational) codes per PdhErrno.IsError. It is a
+// manual wrapper layer kept separate from the generated bindings themselves,
+// since mkwinsyscall has no way to know PDH's error-severity convention.
+func pdhErrnoOrNil(r uintptr) error {
+	e := PdhErrno(r)
+	if !e.IsError() {
+		return nil
+	}
+	return e
+}
+
+// PdhOpenQuery creates a new query that is collected from the local
+// computer. Call PdhCloseQuery to release it.
+func PdhOpenQuery() (PDH_HQUERY, error) {
+	var query PDH_HQUERY
+	if err := pdhErrnoOrNil(pdhOpenQuery(nil, 0, &query)); err != nil {
+		return 0, err
+	}
+	return query, nil
+}
+
+// PdhCloseQuery closes all counters contained in the query, closes all
+// handles related to the query, and frees all memory associated with it.
+func PdhCloseQuery(query PDH_HQUERY) error {
+	return pdhErrnoOrNil(pdhCloseQuery(query))
+}
+
+// PdhAddCounter adds the specified counter, in the current locale, to the
+// query.
+func PdhAddCounter(query PDH_HQUERY, counterPath string) (PDH_HCOUNTER, error) {
+	counterPathPtr, err := windows.UTF16PtrFromString(counterPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var counter PDH_HCOUNTER
+	if err := pdhErrnoOrNil(pdhAddCounter(query, counterPathPtr, 0, &counter)); err != nil {
+		return 0, err
+	}
+	return counter, nil
+}
+
+// PdhAddEnglishCounter adds the specified counter to the query, identifying
+// it by its English-language name regardless of the system locale.
+func PdhAddEnglishCounter(query PDH_HQUERY, counterPath string) (PDH_HCOUNTER, error) {
+	counterPathPtr, err := windows.UTF16PtrFromString(counterPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var counter PDH_HCOUNTER
+	if err := pdhErrnoOrNil(pdhAddEnglishCounter(query, counterPathPtr, 0, &counter)); err != nil {
+		return 0, err
+	}
+	return counter, nil
+}
+
+// PdhRemoveCounter removes a counter from its query.
+func PdhRemoveCounter(counter PDH_HCOUNTER) error {
+	return pdhErrnoOrNil(pdhRemoveCounter(counter))
+}
+
+// PdhCollectQueryData collects the current raw data value for all counters
+// in the query.
+func PdhCollectQueryData(query PDH_HQUERY) error {
+	return pdhErrnoOrNil(pdhCollectQueryData(query))
+}
+
+// PdhCollectQueryDataWithTime collects the current raw data value for all
+// counters in the query and returns the timestamp (as Windows FILETIME) of
+// when the data was collected.
+func PdhCollectQueryDataWithTime(query PDH_HQUERY) (int64, error) {
+	var timeStamp int64
+	if err := pdhErrnoOrNil(pdhCollectQueryDataWithTime(query, &timeStamp)); err != nil {
+		return 0, err
+	}
+	return timeStamp, nil
+}
+
+// PdhGetFormattedCounterValue computes a displayable value for the counter,
+// in the given format. PdhFmtNoCap100 is always OR'd in so that callers see
+// the raw computed value and can decide for themselves whether to clamp it.
+func PdhGetFormattedCounterValue(counter PDH_HCOUNTER, format PdhCounterFormat) (PDH_FMT_COUNTERVALUE, error) {
+	var value PDH_FMT_COUNTERVALUE
+	if err := pdhErrnoOrNil(pdhGetFormattedCounterValue(counter, format|PdhFmtNoCap100, nil, &value)); err != nil {
+		return PDH_FMT_COUNTERVALUE{}, err
+	}
+	return value, nil
+}
+
+// PdhGetFormattedCounterArray computes a displayable value for each instance
+// of a multi-instance counter (such as "\Process(*)\% Processor Time"),
+// growing buf as needed. It returns the raw PDH_FMT_COUNTERVALUE_ITEM buffer
+// and the number of items in it for the caller to parse.
+func PdhGetFormattedCounterArray(counter PDH_HCOUNTER, format PdhCounterFormat, buf []byte) ([]byte, uint32, error) {
+	var bufferSize, bufferCount uint32
+	var itemBuffer *byte
+	if len(buf) > 0 {
+		bufferSize = uint32(len(buf))
+		itemBuffer = &buf[0]
+	}
+
+	err := pdhErrnoOrNil(pdhGetFormattedCounterArray(counter, format|PdhFmtNoCap100, &bufferSize, &bufferCount, itemBuffer))
+	if err == PdhErrno(PDH_MORE_DATA) {
+		grown := make([]byte, bufferSize)
+		if err := pdhErrnoOrNil(pdhGetFormattedCounterArray(counter, format|PdhFmtNoCap100, &bufferSize, &bufferCount, &grown[0])); err != nil {
+			return nil, 0, err
+		}
+		return grown, bufferCount, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf[:bufferSize], bufferCount, nil
+}
+
+// PdhExpandWildCardPath examines the local computer for counter paths that
+// match the given wildcard path (e.g. "\Processor(*)\% Processor Time") and
+// returns the matched paths.
+func PdhExpandWildCardPath(wildCardPath string) ([]string, error) {
+	wildCardPathPtr, err := windows.UTF16PtrFromString(wildCardPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathListLength uint32
+	err = pdhErrnoOrNil(pdhExpandWildCardPath(nil, wildCardPathPtr, nil, &pathListLength))
+	if err != nil && err != PdhErrno(PDH_MORE_DATA) {
+		return nil, err
+	}
+	if pathListLength == 0 {
+		return nil, nil
+	}
+
+	buf := make([]uint16, pathListLength)
+	if err := pdhErrnoOrNil(pdhExpandWildCardPath(nil, wildCardPathPtr, &buf[0], &pathListLength)); err != nil {
+		return nil, err
+	}
+	return splitNulTerminatedStrings(buf), nil
+}
+
+// PdhLookupPerfNameByIndex translates a language-neutral counter/object
+// index into its name in the system's current locale. Query does not call
+// it: Query.AddEnglishCounter resolves English-language paths through
+// PdhAddEnglishCounter instead.
+func PdhLookupPerfNameByIndex(nameIndex uint32) (string, error) {
+	var size uint32
+	err := pdhErrnoOrNil(pdhLookupPerfNameByIndex(nil, nameIndex, nil, &size))
+	if err != nil && err != PdhErrno(PDH_MORE_DATA) {
+		return "", err
+	}
+	if size == 0 {
+		return "", nil
+	}
+
+	buf := make([]uint16, size)
+	if err := pdhErrnoOrNil(pdhLookupPerfNameByIndex(nil, nameIndex, &buf[0], &size)); err != nil {
+		return "", err
+	}
+	return windows.UTF16ToString(buf), nil
+}
+
+// PdhGetCounterInfo retrieves information about a counter, such as the data
+// size, counter type, path, and user-supplied data values, growing buf as
+// needed. It returns the raw PDH_COUNTER_INFO buffer for the caller to parse.
+func PdhGetCounterInfo(counter PDH_HCOUNTER, buf []byte) ([]byte, error) {
+	bufferSize := uint32(len(buf))
+	var itemBuffer *byte
+	if bufferSize > 0 {
+		itemBuffer = &buf[0]
+	}
+
+	err := pdhErrnoOrNil(pdhGetCounterInfo(counter, 0, &bufferSize, itemBuffer))
+	if err == PdhErrno(PDH_MORE_DATA) {
+		grown := make([]byte, bufferSize)
+		if err := pdhErrnoOrNil(pdhGetCounterInfo(counter, 0, &bufferSize, &grown[0])); err != nil {
+			return nil, err
+		}
+		return grown, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf[:bufferSize], nil
+}
+
+// splitNulTerminatedStrings splits a Windows-style REG_MULTI_SZ buffer (a
+// sequence of NUL-terminated UTF-16 strings ending with an extra NUL) into
+// individual Go strings.
+func splitNulTerminatedStrings(buf []uint16) []string {
+	var out []string
+	start := 0
+	for i, c := range buf {
+		if c != 0 {
+			continue
+		}
+		if i > start {
+			out = append(out, windows.UTF16ToString(buf[start:i]))
+		}
+		start = i + 1
+	}
+	return out
+}
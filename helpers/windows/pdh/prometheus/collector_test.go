@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/elastic/elastic-agent-libs/helpers/windows/pdh"
+)
+
+func TestFormattedValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  pdh.PDH_FMT_COUNTERVALUE
+		format pdh.PdhCounterFormat
+		want   float64
+	}{
+		{"long", pdh.NewLongCounterValue(42), pdh.PdhFmtLong, 42},
+		{"long with scaling flags", pdh.NewLongCounterValue(42), pdh.PdhFmtLong | pdh.PdhFmtNoCap100, 42},
+		{"large", pdh.NewLargeCounterValue(1 << 40), pdh.PdhFmtLarge, float64(int64(1 << 40))},
+		{"double", pdh.NewDoubleCounterValue(3.5), pdh.PdhFmtDouble, 3.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formattedValue(tt.value, tt.format); got != tt.want {
+				t.Errorf("formattedValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+import (
+	"fmt"
+	"strings"
+)
+
+// counterInfo tracks everything Query needs to remember about a counter it
+// has added, since the PDH handle alone does not carry the original path,
+// the requested format, or whether the path came from a wildcard expansion.
+type counterInfo struct {
+	requestedPath string
+	path          string
+	instance      string
+	format        PdhCounterFormat
+	handle        PDH_HCOUNTER
+}
+
+// Query wraps a PDH_HQUERY and the set of counters added to it, handling the
+// bookkeeping that the raw PDH API leaves to the caller: first-collect
+// double-buffering, wildcard expansion, and locale-independent counter
+// names. It is not safe for concurrent use.
+type Query struct {
+	handle    PDH_HQUERY
+	counters  map[string]*counterInfo
+	collected int
+}
+
+// NewQuery allocates a Query. Call Open before adding counters.
+func NewQuery() *Query {
+	return &Query{
+		counters: make(map[string]*counterInfo),
+	}
+}
+
+// Open creates the underlying PDH query. It must be called before
+// AddCounter, AddEnglishCounter, or CollectData.
+func (q *Query) Open() error {
+	handle, err := PdhOpenQuery()
+	if err != nil {
+		return fmt.Errorf("failed opening PDH query: %w", err)
+	}
+	q.handle = handle
+	return nil
+}
+
+// AddCounter adds path to the query, formatting collected values according
+// to format. If path contains a wildcard instance (e.g.
+// "\Processor(*)\% Processor Time") it is expanded into one counter per
+// matched instance.
+func (q *Query) AddCounter(path string, instance string, format PdhCounterFormat) error {
+	return q.addCounter(path, instance, format, PdhAddCounter)
+}
+
+// AddEnglishCounter behaves like AddCounter but resolves path using its
+// English-language counter and object names, regardless of the system
+// locale. Configuration written against English counter paths therefore
+// works unmodified on non-English Windows installs.
+func (q *Query) AddEnglishCounter(path string, instance string, format PdhCounterFormat) error {
+	return q.addCounter(path, instance, format, PdhAddEnglishCounter)
+}
+
+func (q *Query) addCounter(path, instance string, format PdhCounterFormat, add func(PDH_HQUERY, string) (PDH_HCOUNTER, error)) error {
+	if strings.Contains(path, "(*)") {
+		paths, err := PdhExpandWildCardPath(path)
+		if err != nil {
+			return fmt.Errorf("failed expanding wildcard path %q: %w", path, err)
+		}
+		for _, expanded := range paths {
+			if err := q.addSingleCounter(path, expanded, instanceFromPath(expanded), format, add); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return q.addSingleCounter(path, path, instance, format, add)
+}
+
+func (q *Query) addSingleCounter(requestedPath, path, instance string, format PdhCounterFormat, add func(PDH_HQUERY, string) (PDH_HCOUNTER, error)) error {
+	handle, err := add(q.handle, path)
+	if err != nil {
+		return fmt.Errorf("failed adding counter %q: %w", path, err)
+	}
+
+	q.counters[path] = &counterInfo{
+		requestedPath: requestedPath,
+		path:          path,
+		instance:      instance,
+		format:        format,
+		handle:        handle,
+	}
+	return nil
+}
+
+// instanceFromPath extracts the instance name from a fully-qualified
+// counter path such as "\PhysicalDisk(0 C:)\Disk Reads/sec" -> "0 C:".
+func instanceFromPath(path string) string {
+	open := strings.IndexByte(path, '(')
+	shut := strings.IndexByte(path, ')')
+	if open == -1 || shut == -1 || shut < open {
+		return ""
+	}
+	return path[open+1 : shut]
+}
+
+// CollectData collects the current value for every counter in the query.
+// Some counters (rates and other two-sample calculations) are not valid
+// until a second collection, so CollectData transparently issues a second
+// PdhCollectQueryData call the first time it is invoked.
+func (q *Query) CollectData() error {
+	if err := PdhCollectQueryData(q.handle); err != nil {
+		return fmt.Errorf("failed collecting PDH query data: %w", err)
+	}
+	q.collected++
+
+	if q.collected == 1 {
+		// Rate counters require two samples before PdhGetFormattedCounterValue
+		// returns PDH_CSTATUS_VALID_DATA, so bootstrap the second sample now
+		// rather than surprising the caller with PDH_CALC_NEGATIVE_VALUE (or
+		// similar) on their first read.
+		if err := PdhCollectQueryData(q.handle); err != nil {
+			return fmt.Errorf("failed collecting second PDH query data sample: %w", err)
+		}
+		q.collected++
+	}
+	return nil
+}
+
+// CounterValue holds a single formatted counter reading.
+type CounterValue struct {
+	Instance string
+	Value    PDH_FMT_COUNTERVALUE
+}
+
+// GetFormattedCounterValues returns the latest formatted value(s) for every
+// counter added to the query, keyed by counter path. A path added without a
+// wildcard has exactly one CounterValue; a path expanded from a wildcard
+// contributes one CounterValue per matched instance, all keyed under the
+// original wildcard path.
+func (q *Query) GetFormattedCounterValues() (map[string][]CounterValue, error) {
+	results := make(map[string][]CounterValue, len(q.counters))
+	for path, info := range q.counters {
+		value, err := PdhGetFormattedCounterValue(info.handle, info.format)
+		if err != nil {
+			return nil, fmt.Errorf("failed formatting counter value for %q: %w", path, err)
+		}
+		results[info.requestedPath] = append(results[info.requestedPath], CounterValue{Instance: info.instance, Value: value})
+	}
+	return results, nil
+}
+
+// Close closes the query and every counter handle it owns.
+func (q *Query) Close() error {
+	if q.handle == 0 {
+		return nil
+	}
+	err := PdhCloseQuery(q.handle)
+	q.handle = 0
+	q.counters = make(map[string]*counterInfo)
+	return err
+}
@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+import "testing"
+
+func TestInstanceFromPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"instance present", `\PhysicalDisk(0 C:)\Disk Reads/sec`, "0 C:"},
+		{"no parens", `\Memory\Available Bytes`, ""},
+		{"unmatched open paren", `\PhysicalDisk(0 C:\Disk Reads/sec`, ""},
+		{"unmatched close paren", `\PhysicalDisk0 C:)\Disk Reads/sec`, ""},
+		{"empty instance", `\PhysicalDisk()\Disk Reads/sec`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := instanceFromPath(tt.path); got != tt.want {
+				t.Errorf("instanceFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestQueryAddCounterWildcardGrouping verifies that a wildcard path expanded
+// into several instance-specific counters is still tracked under the
+// originally requested path, so GetFormattedCounterValues can return all of
+// them as one slice keyed by that path.
+func TestQueryAddCounterWildcardGrouping(t *testing.T) {
+	q := NewQuery()
+
+	wildcard := `\PhysicalDisk(*)\Disk Reads/sec`
+	expanded := []string{
+		`\PhysicalDisk(0 C:)\Disk Reads/sec`,
+		`\PhysicalDisk(1 D:)\Disk Reads/sec`,
+	}
+
+	add := func(PDH_HQUERY, string) (PDH_HCOUNTER, error) { return 1, nil }
+	for _, path := range expanded {
+		if err := q.addSingleCounter(wildcard, path, instanceFromPath(path), PdhFmtDouble, add); err != nil {
+			t.Fatalf("addSingleCounter(%q) failed: %v", path, err)
+		}
+	}
+
+	if len(q.counters) != len(expanded) {
+		t.Fatalf("len(q.counters) = %d, want %d", len(q.counters), len(expanded))
+	}
+	for _, path := range expanded {
+		info, ok := q.counters[path]
+		if !ok {
+			t.Fatalf("q.counters[%q] missing", path)
+		}
+		if info.requestedPath != wildcard {
+			t.Errorf("q.counters[%q].requestedPath = %q, want %q", path, info.requestedPath, wildcard)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+import (
+	"fmt"
+
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// CounterConfig describes a single counter that a Reader should collect and
+// how to surface it in the resulting event.
+type CounterConfig struct {
+	// Query is the PDH counter path, e.g. "\Processor(*)\% Processor Time".
+	Query string
+	// Field is the dotted mapstr.M key the formatted value is written to,
+	// e.g. "processor.time.total.pct".
+	Field string
+	// Format selects the data type PDH formats the value as.
+	Format PdhCounterFormat
+	// EnglishCounter resolves Query using its English-language name,
+	// independent of the system's locale.
+	EnglishCounter bool
+	// InstanceLabel, when set, names the field that the counter's instance
+	// (e.g. "0,1" for a multi-core processor) is written under, alongside
+	// Field, so multi-instance counters can be told apart in the event.
+	InstanceLabel string
+}
+
+// Reader collects a fixed set of counters on each call to Read and returns
+// them as mapstr.M events ready for a Beats/Elastic Agent input to publish.
+type Reader struct {
+	query    *Query
+	counters []CounterConfig
+}
+
+// NewReader builds a Reader for the given counters and opens the underlying
+// PDH query. Read must not be called until the returned error is nil.
+func NewReader(counters []CounterConfig) (*Reader, error) {
+	query := NewQuery()
+	if err := query.Open(); err != nil {
+		return nil, err
+	}
+
+	r := &Reader{query: query, counters: counters}
+	for _, c := range counters {
+		var err error
+		if c.EnglishCounter {
+			err = query.AddEnglishCounter(c.Query, "", c.Format)
+		} else {
+			err = query.AddCounter(c.Query, "", c.Format)
+		}
+		if err != nil {
+			query.Close()
+			return nil, fmt.Errorf("failed adding counter %q to reader: %w", c.Query, err)
+		}
+	}
+
+	return r, nil
+}
+
+// Read collects the current value of every configured counter and returns
+// one event per distinct instance. Counters that apply to a single
+// (non-wildcard) path all contribute to the same, single event.
+func (r *Reader) Read() ([]mapstr.M, error) {
+	if err := r.query.CollectData(); err != nil {
+		return nil, err
+	}
+
+	values, err := r.query.GetFormattedCounterValues()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(map[string]mapstr.M)
+	order := make([]string, 0, len(r.counters))
+	eventFor := func(instance string) mapstr.M {
+		ev, ok := events[instance]
+		if !ok {
+			ev = mapstr.M{}
+			events[instance] = ev
+			order = append(order, instance)
+		}
+		return ev
+	}
+
+	for _, c := range r.counters {
+		for _, cv := range values[c.Query] {
+			ev := eventFor(cv.Instance)
+			ev.Put(c.Field, formatValue(cv.Value, c.Format))
+			if c.InstanceLabel != "" && cv.Instance != "" {
+				ev.Put(c.InstanceLabel, cv.Instance)
+			}
+		}
+	}
+
+	out := make([]mapstr.M, 0, len(order))
+	for _, instance := range order {
+		out = append(out, events[instance])
+	}
+	return out, nil
+}
+
+// formatValue extracts the Go value matching format out of a
+// PDH_FMT_COUNTERVALUE union.
+func formatValue(v PDH_FMT_COUNTERVALUE, format PdhCounterFormat) interface{} {
+	switch format &^ (PdhFmtNoScale | PdhFmtNoCap100 | PdhFmtMultiply1000) {
+	case PdhFmtLong:
+		return v.LongValue()
+	case PdhFmtLarge:
+		return v.LargeValue()
+	default:
+		return v.DoubleValue()
+	}
+}
+
+// Close releases the Reader's underlying PDH query.
+func (r *Reader) Close() error {
+	return r.query.Close()
+}
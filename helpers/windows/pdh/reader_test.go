@@ -0,0 +1,43 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+import "testing"
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  PDH_FMT_COUNTERVALUE
+		format PdhCounterFormat
+		want   interface{}
+	}{
+		{"long", NewLongCounterValue(42), PdhFmtLong, int32(42)},
+		{"long with scaling flags", NewLongCounterValue(42), PdhFmtLong | PdhFmtNoCap100 | PdhFmtNoScale, int32(42)},
+		{"large", NewLargeCounterValue(1 << 40), PdhFmtLarge, int64(1 << 40)},
+		{"double", NewDoubleCounterValue(3.5), PdhFmtDouble, 3.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatValue(tt.value, tt.format); got != tt.want {
+				t.Errorf("formatValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build windows
+
+package pdh
+
+// The zsyscall_windows.go bindings below are generated from the //sys
+// comments in this file with mkwinsyscall:
+//
+//	go run golang.org/x/sys/windows/mkwinsyscall -output zsyscall_windows.go syscall_windows.go
+
+//sys pdhOpenQuery(dataSource *uint16, userData uintptr, query *PDH_HQUERY) (r uintptr) = pdh.PdhOpenQuery
+//sys pdhCloseQuery(query PDH_HQUERY) (r uintptr) = pdh.PdhCloseQuery
+//sys pdhAddCounter(query PDH_HQUERY, counterPath *uint16, userData uintptr, counter *PDH_HCOUNTER) (r uintptr) = pdh.PdhAddCounterW
+//sys pdhAddEnglishCounter(query PDH_HQUERY, counterPath *uint16, userData uintptr, counter *PDH_HCOUNTER) (r uintptr) = pdh.PdhAddEnglishCounterW
+//sys pdhRemoveCounter(counter PDH_HCOUNTER) (r uintptr) = pdh.PdhRemoveCounter
+//sys pdhCollectQueryData(query PDH_HQUERY) (r uintptr) = pdh.PdhCollectQueryData
+//sys pdhCollectQueryDataWithTime(query PDH_HQUERY, timeStamp *int64) (r uintptr) = pdh.PdhCollectQueryDataWithTime
+//sys pdhGetFormattedCounterValue(counter PDH_HCOUNTER, format PdhCounterFormat, counterType *uint32, value *PDH_FMT_COUNTERVALUE) (r uintptr) = pdh.PdhGetFormattedCounterValue
+//sys pdhGetFormattedCounterArray(counter PDH_HCOUNTER, format PdhCounterFormat, bufferSize *uint32, bufferCount *uint32, itemBuffer *byte) (r uintptr) = pdh.PdhGetFormattedCounterArrayW
+//sys pdhExpandWildCardPath(dataSource *uint16, wildCardPath *uint16, expandedPathList *uint16, pathListLength *uint32) (r uintptr) = pdh.PdhExpandWildCardPathW
+//sys pdhLookupPerfNameByIndex(machineName *uint16, nameIndex uint32, nameBuffer *uint16, nameBufferSize *uint32) (r uintptr) = pdh.PdhLookupPerfNameByIndexW
+//sys pdhGetCounterInfo(counter PDH_HCOUNTER, retrieveExplainText uint32, bufferSize *uint32, buffer *byte) (r uintptr) = pdh.PdhGetCounterInfoW
+//sys pdhBindInputDataSource(dataSource *PDH_HLOG, logFileNameList *uint16) (r uintptr) = pdh.PdhBindInputDataSourceW
+//sys pdhOpenQueryH(dataSource PDH_HLOG, userData uintptr, query *PDH_HQUERY) (r uintptr) = pdh.PdhOpenQueryH
+//sys pdhCloseLog(dataSource PDH_HLOG) (r uintptr) = pdh.PdhCloseLog
+//sys pdhEnumObjects(dataSource *uint16, machineName *uint16, objectList *uint16, objectListLength *uint32, detailLevel uint32, refresh uint32) (r uintptr) = pdh.PdhEnumObjectsW
+//sys pdhEnumObjectItems(dataSource *uint16, machineName *uint16, objectName *uint16, counterList *uint16, counterListLength *uint32, instanceList *uint16, instanceListLength *uint32, detailLevel uint32, flags uint32) (r uintptr) = pdh.PdhEnumObjectItemsW